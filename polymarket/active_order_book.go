@@ -0,0 +1,363 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// UserEvent 是用户数据 WebSocket 推送的一条订单状态变更
+type UserEvent struct {
+	EventID    string // 用于去重，同一事件可能因为重连被重复推送
+	OrderHash  string
+	TokenID    string
+	Type       UserEventType
+	FilledSize float64
+}
+
+// UserEventType 枚举用户数据流可能推送的订单状态
+type UserEventType string
+
+const (
+	UserEventFilled          UserEventType = "FILLED"
+	UserEventPartiallyFilled UserEventType = "PARTIALLY_FILLED"
+	UserEventCanceled        UserEventType = "CANCELED"
+	UserEventExpired         UserEventType = "EXPIRED"
+)
+
+// UserDataStream 由调用方提供，负责把底层 WebSocket 连接适配成一个事件 channel
+type UserDataStream interface {
+	Events() <-chan UserEvent
+}
+
+// PriceLevel 是 Snapshot() 中单个价格档位的挂单视图，供做市等外部包读取
+type PriceLevel struct {
+	TokenID string
+	Side    string
+	Price   float64
+	Size    float64 // size - filled，即仍然挂在盘口的数量
+}
+
+type trackedOrder struct {
+	hash    string
+	tokenID string
+	side    string
+	price   float64
+	size    float64
+	filled  float64
+	active  bool
+}
+
+// ActiveOrderBook 跟踪由 CreateAndPostOrder 产生的每一笔订单（按 client order hash 索引），
+// 订阅用户数据 WebSocket 流并分发成交/撤单回调，同时定期用 REST GetOpenOrders 做对账，
+// 修复可能被漏掉的 WebSocket 消息。
+type ActiveOrderBook struct {
+	client *ClobClient
+
+	reconcileInterval time.Duration
+
+	mu         sync.Mutex
+	orders     map[string]*trackedOrder
+	seenEvents map[string]struct{} // 去重：已处理过的 EventID
+
+	onFilled          []func(hash string)
+	onPartiallyFilled []func(hash, tokenID string, filledSize float64)
+	onCanceled        []func(hash string)
+	onExpired         []func(hash string)
+}
+
+// NewActiveOrderBook 构造一个绑定到 client 的挂单跟踪器
+func NewActiveOrderBook(client *ClobClient, reconcileInterval time.Duration) *ActiveOrderBook {
+	if reconcileInterval <= 0 {
+		reconcileInterval = 30 * time.Second
+	}
+	return &ActiveOrderBook{
+		client:            client,
+		reconcileInterval: reconcileInterval,
+		orders:            make(map[string]*trackedOrder),
+		seenEvents:        make(map[string]struct{}),
+	}
+}
+
+// Track 登记一笔新提交的订单
+func (b *ActiveOrderBook) Track(order *SignedOrder, tokenID, side string, price, size float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.orders[order.Hash()] = &trackedOrder{
+		hash:    order.Hash(),
+		tokenID: tokenID,
+		side:    side,
+		price:   price,
+		size:    size,
+		active:  true,
+	}
+}
+
+// OnFilled 注册完全成交回调
+func (b *ActiveOrderBook) OnFilled(cb func(hash string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onFilled = append(b.onFilled, cb)
+}
+
+// OnPartiallyFilled 注册部分成交回调
+func (b *ActiveOrderBook) OnPartiallyFilled(cb func(hash, tokenID string, filledSize float64)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onPartiallyFilled = append(b.onPartiallyFilled, cb)
+}
+
+// OnCanceled 注册撤单回调
+func (b *ActiveOrderBook) OnCanceled(cb func(hash string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onCanceled = append(b.onCanceled, cb)
+}
+
+// OnExpired 注册过期回调
+func (b *ActiveOrderBook) OnExpired(cb func(hash string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onExpired = append(b.onExpired, cb)
+}
+
+// Subscribe 启动一个后台 goroutine 消费用户数据流事件，直到 ctx 被取消
+func (b *ActiveOrderBook) Subscribe(ctx context.Context, stream UserDataStream) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-stream.Events():
+				if !ok {
+					return
+				}
+				b.handleEvent(ev)
+			}
+		}
+	}()
+}
+
+// handleEvent 去重后按事件类型更新跟踪状态并分发回调
+func (b *ActiveOrderBook) handleEvent(ev UserEvent) {
+	b.mu.Lock()
+
+	if ev.EventID != "" {
+		if _, seen := b.seenEvents[ev.EventID]; seen {
+			b.mu.Unlock()
+			return
+		}
+		b.seenEvents[ev.EventID] = struct{}{}
+	}
+
+	order, ok := b.orders[ev.OrderHash]
+	if !ok {
+		b.mu.Unlock()
+		return
+	}
+
+	switch ev.Type {
+	case UserEventFilled:
+		order.filled = order.size
+		order.active = false
+		delete(b.orders, ev.OrderHash)
+	case UserEventPartiallyFilled:
+		order.filled = ev.FilledSize
+	case UserEventCanceled, UserEventExpired:
+		order.active = false
+		delete(b.orders, ev.OrderHash)
+	}
+
+	b.mu.Unlock()
+
+	switch ev.Type {
+	case UserEventFilled:
+		b.dispatch(b.onFilled, ev.OrderHash)
+	case UserEventPartiallyFilled:
+		b.dispatchPartial(ev.OrderHash, ev.TokenID, ev.FilledSize)
+	case UserEventCanceled:
+		b.dispatch(b.onCanceled, ev.OrderHash)
+	case UserEventExpired:
+		b.dispatch(b.onExpired, ev.OrderHash)
+	}
+}
+
+func (b *ActiveOrderBook) dispatch(callbacks []func(string), hash string) {
+	for _, cb := range callbacks {
+		cb(hash)
+	}
+}
+
+func (b *ActiveOrderBook) dispatchPartial(hash, tokenID string, filled float64) {
+	for _, cb := range b.onPartiallyFilled {
+		cb(hash, tokenID, filled)
+	}
+}
+
+// Replace 撤销旧订单并提交新订单，两步不是原子的：先撤销，成功后再下新单，
+// 若新单提交失败，旧订单已经被撤销，调用方需要自行决定是否重试。
+func (b *ActiveOrderBook) Replace(oldHash string, newArgs *OrderArgs, options *PartialCreateOrderOptions) (*SignedOrder, error) {
+	if err := b.client.CancelPendingOrder(oldHash); err != nil {
+		return nil, fmt.Errorf("active order book: cancel old order %s: %w", oldHash, err)
+	}
+	b.Remove(oldHash)
+
+	order, err := b.client.CreateOrder(newArgs, options)
+	if err != nil {
+		return nil, fmt.Errorf("active order book: create replacement order: %w", err)
+	}
+	if _, err := b.client.SubmitOrder(order, OrderTypeGTC); err != nil {
+		return nil, fmt.Errorf("active order book: post replacement order: %w", err)
+	}
+
+	b.Track(order, newArgs.TokenID, newArgs.Side, newArgs.Price, newArgs.Size)
+	return order, nil
+}
+
+// UntouchedHashes 返回某个 token 下尚未被部分成交的挂单哈希，
+// 供做市等场景在重新报价时只撤销未成交的层，保留已部分成交的层。
+func (b *ActiveOrderBook) UntouchedHashes(tokenID string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hashes := make([]string, 0)
+	for hash, o := range b.orders {
+		if o.tokenID == tokenID && o.filled == 0 {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes
+}
+
+// Remove 从跟踪表中移除一笔订单（不发起撤单请求）
+func (b *ActiveOrderBook) Remove(hash string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.orders, hash)
+}
+
+// CancelAll 批量撤销某个 token 下所有被跟踪的挂单
+func (b *ActiveOrderBook) CancelAll(tokenID string) error {
+	b.mu.Lock()
+	hashes := make([]string, 0)
+	for hash, o := range b.orders {
+		if o.tokenID == tokenID {
+			hashes = append(hashes, hash)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, hash := range hashes {
+		if err := b.client.CancelPendingOrder(hash); err != nil {
+			return fmt.Errorf("active order book: cancel %s: %w", hash, err)
+		}
+		b.Remove(hash)
+	}
+	return nil
+}
+
+// GracefulShutdown 撤销所有被跟踪的挂单，通常在策略退出前调用
+func (b *ActiveOrderBook) GracefulShutdown(ctx context.Context) error {
+	b.mu.Lock()
+	hashes := make([]string, 0, len(b.orders))
+	for hash := range b.orders {
+		hashes = append(hashes, hash)
+	}
+	b.mu.Unlock()
+
+	for _, hash := range hashes {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if err := b.client.CancelPendingOrder(hash); err != nil {
+			return fmt.Errorf("active order book: shutdown cancel %s: %w", hash, err)
+		}
+		b.Remove(hash)
+	}
+	return nil
+}
+
+// Snapshot 返回当前每个 token、每个价格档位的在场挂单量，供做市代码决定是否需要重新报价
+func (b *ActiveOrderBook) Snapshot() map[string][]PriceLevel {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make(map[string][]PriceLevel)
+	for _, o := range b.orders {
+		levels := out[o.tokenID]
+		merged := false
+		for i := range levels {
+			if levels[i].Side == o.side && levels[i].Price == o.price {
+				levels[i].Size += o.size - o.filled
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			levels = append(levels, PriceLevel{
+				TokenID: o.tokenID,
+				Side:    o.side,
+				Price:   o.price,
+				Size:    o.size - o.filled,
+			})
+		}
+		out[o.tokenID] = levels
+	}
+	return out
+}
+
+// ReconcileLoop 周期性调用 REST GetOpenOrders 对账，修复被 WebSocket 漏掉的消息：
+// 本地记录但交易所已不存在的订单视为已撤销/已成交并移除；阻塞直到 ctx 被取消。
+func (b *ActiveOrderBook) ReconcileLoop(ctx context.Context) error {
+	ticker := time.NewTicker(b.reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := b.reconcile(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (b *ActiveOrderBook) reconcile() error {
+	b.mu.Lock()
+	tokenIDs := make(map[string]struct{})
+	for _, o := range b.orders {
+		tokenIDs[o.tokenID] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	for tokenID := range tokenIDs {
+		open, err := b.client.GetOpenOrders(tokenID)
+		if err != nil {
+			return fmt.Errorf("active order book: reconcile %s: %w", tokenID, err)
+		}
+
+		stillOpen := make(map[string]struct{}, len(open))
+		for _, o := range open {
+			stillOpen[o.Hash] = struct{}{}
+		}
+
+		b.mu.Lock()
+		for hash, o := range b.orders {
+			if o.tokenID != tokenID {
+				continue
+			}
+			if _, ok := stillOpen[hash]; !ok {
+				delete(b.orders, hash)
+			}
+		}
+		b.mu.Unlock()
+	}
+
+	return nil
+}
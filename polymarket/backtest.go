@@ -0,0 +1,454 @@
+package polymarket
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// BookSource 为回测引擎提供历史订单簿快照，按 token 和时间索引。
+// Book 应返回小于等于 t 的最新快照；没有数据时返回 ok=false。
+type BookSource interface {
+	Book(tokenID string, t time.Time) (*OrderBook, bool)
+}
+
+// Fill 描述回测撮合引擎产生的一笔成交
+type Fill struct {
+	TokenID string
+	Side    string
+	Price   float64
+	Size    float64
+	Fee     float64
+	IsMaker bool
+	Time    time.Time
+	Hash    string
+}
+
+// BacktestConfig 配置回测/纸上交易模式
+type BacktestConfig struct {
+	// Client 提供签名、builder 等与实盘一致的基础能力，回测不会通过它发起网络请求
+	Client *ClobClient
+
+	StartTime time.Time
+	EndTime   time.Time
+
+	// MakerFeeRate/TakerFeeRate 单位为 bps，替代 resolveFeeRate 的实时查询。
+	// 提交时立即吃掉对手盘的成交按 TakerFeeRate 计费；挂单在后续 Advance 中
+	// 被对手盘吃到的成交按 MakerFeeRate 计费。
+	MakerFeeRate int
+	TakerFeeRate int
+
+	// StartingBalances 按 tokenID 记录起始持仓份数，USDC 现金用键 "USDC" 记录
+	StartingBalances map[string]float64
+
+	Book BookSource
+}
+
+// Backtester 是一个纯内存的撮合引擎，取代实盘的下单/撤单网络调用，
+// 用历史订单簿重放来估算策略的 PnL。
+type Backtester struct {
+	cfg BacktestConfig
+
+	mu       sync.Mutex
+	now      time.Time
+	balances map[string]float64
+	resting  map[string][]*backtestOrder // tokenID -> 挂单队列
+	pending  map[string]*pendingOrder    // hash -> CreateOrder 阶段暂存的下单参数，等待 PostOrder 提交
+	ledgers  map[string]*tokenLedger     // tokenID -> 当前模拟时间 now 下尚未消耗完的对手盘快照
+	fills    []Fill
+	onFill   []func(Fill)
+}
+
+// tokenLedger 缓存某个 token 在某一模拟时间 at 的对手盘剩余深度。submit() 每次只传入
+// 一笔订单给 matchAgainstBook，如果每次都从 BookSource 重新建一份快照，同一个 now 下
+// 连续提交的多笔订单（例如做市引擎一轮刷新里背靠背挂出的 N 层）会各自对着同一份
+// 未消耗的快照撮合，凭空制造出重复的流动性。同一 token、同一 now 下的所有撮合改为
+// 共享并递减这一份快照，时间推进到新的 t 后才重新从 BookSource 拉取。
+type tokenLedger struct {
+	at  time.Time
+	ask *levelLedger
+	bid *levelLedger
+}
+
+type backtestOrder struct {
+	hash      string
+	tokenID   string
+	side      string
+	price     float64
+	size      float64
+	filled    float64
+	orderType OrderType
+	expires   time.Time
+	placedAt  time.Time
+}
+
+// pendingOrder 是 CreateOrder 计算出的下单参数，在 PostOrder 被调用前暂存，
+// 因为 PostOrder 只拿到一笔已签名订单，无法再拿到原始的 tokenID/side/price/size。
+type pendingOrder struct {
+	tokenID    string
+	side       string
+	price      float64
+	size       float64
+	expiration int
+}
+
+// NewBacktestClient 把一个已经完成签名配置的 ClobClient 切换到回测执行模式：
+// CreateAndPostOrder、SubmitOrder 与 CancelPendingOrder 从此路由到内存撮合引擎，
+// 而不是实盘 API；直接调用 PostOrder 或 CancelOrder 仍会发往实盘 API。
+// 返回的 *ClobClient 的公开方法集合与实盘相同。
+func NewBacktestClient(cfg BacktestConfig) *ClobClient {
+	c := cfg.Client
+	c.backtester = &Backtester{
+		cfg:      cfg,
+		now:      cfg.StartTime,
+		balances: cloneBalances(cfg.StartingBalances),
+		resting:  make(map[string][]*backtestOrder),
+		pending:  make(map[string]*pendingOrder),
+		ledgers:  make(map[string]*tokenLedger),
+	}
+	return c
+}
+
+func cloneBalances(src map[string]float64) map[string]float64 {
+	dst := make(map[string]float64, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// OnFill 注册成交回调，每笔模拟成交以 Fill 调用一次；回测成交不会触发
+// ActiveOrderBook 的 OnFilled/OnPartiallyFilled 等回调
+func (b *Backtester) OnFill(cb func(Fill)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onFill = append(b.onFill, cb)
+}
+
+// Advance 推进回测时钟到 t，并用最新快照重新尝试撮合所有挂单。
+// 此时成交的都是先前已经挂在盘口的订单，按 maker 费率计费。
+func (b *Backtester) Advance(t time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.now = t
+
+	for tokenID, orders := range b.resting {
+		b.resting[tokenID] = b.matchAgainstBook(tokenID, orders, t, true)
+	}
+}
+
+// Balance 返回当前模拟持仓/现金余额
+func (b *Backtester) Balance(key string) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.balances[key]
+}
+
+// Fills 返回截至目前产生的全部成交
+func (b *Backtester) Fills() []Fill {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]Fill, len(b.fills))
+	copy(out, b.fills)
+	return out
+}
+
+// stage 暂存 CreateOrder 阶段算出的下单参数，供随后的 PostOrder 调用取用
+func (b *Backtester) stage(hash, tokenID, side string, price, size float64, expiration int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending[hash] = &pendingOrder{tokenID: tokenID, side: side, price: price, size: size, expiration: expiration}
+}
+
+// submitPending 提交一笔此前由 CreateOrder stage 过的订单，对应实盘的 PostOrder
+func (b *Backtester) submitPending(hash string, orderType OrderType) (interface{}, error) {
+	b.mu.Lock()
+	p, ok := b.pending[hash]
+	if ok {
+		delete(b.pending, hash)
+	}
+	b.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("backtest: no staged order for hash %s, call CreateOrder first", hash)
+	}
+
+	return b.submit(p.tokenID, p.side, p.price, p.size, hash, orderType, p.expiration)
+}
+
+// cancel 撤销一笔仍在挂单队列中的订单，对应实盘的 CancelOrder
+func (b *Backtester) cancel(hash string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for tokenID, orders := range b.resting {
+		for i, o := range orders {
+			if o.hash == hash {
+				b.resting[tokenID] = append(orders[:i:i], orders[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("backtest: order %s not found among resting orders", hash)
+}
+
+// submit 接收一笔已签名订单，按 order_args 的价格/数量与 orderType 语义撮合。
+// 提交时立即吃掉的成交按 taker 费率计费。
+func (b *Backtester) submit(tokenID, side string, price, size float64, hash string, orderType OrderType, expiration int) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	order := &backtestOrder{
+		hash:      hash,
+		tokenID:   tokenID,
+		side:      side,
+		price:     price,
+		size:      size,
+		orderType: orderType,
+		placedAt:  b.now,
+	}
+	if expiration > 0 {
+		order.expires = time.Unix(int64(expiration), 0)
+	}
+
+	remaining := b.matchAgainstBook(tokenID, []*backtestOrder{order}, b.now, false)
+
+	switch orderType {
+	case OrderTypeFOK:
+		if order.filled < order.size {
+			// 未能完全成交：回滚本次成交并拒绝整单
+			b.rollbackFills(order)
+			return map[string]interface{}{"success": false, "errorMsg": "FOK order could not be fully filled"}, nil
+		}
+	case OrderTypeFAK:
+		// 成交剩余部分立即取消，不进入挂单队列
+		remaining = nil
+	default: // GTC / GTD
+		b.resting[tokenID] = append(b.resting[tokenID], remaining...)
+	}
+
+	return map[string]interface{}{
+		"success":   true,
+		"orderHash": hash,
+		"filled":    order.filled,
+	}, nil
+}
+
+// levelLedger 是单侧（asks 或 bids）订单簿快照的可变副本，按消耗情况递减每个
+// 价格档位的剩余量，使同一批订单共享同一份快照而不会相互“凭空”成交。
+type levelLedger struct {
+	prices    []float64
+	remaining []float64
+}
+
+func newLevelLedger(levels []OrderSummary) *levelLedger {
+	ledger := &levelLedger{}
+	for _, level := range levels {
+		price, err := strconv.ParseFloat(level.Price, 64)
+		if err != nil {
+			continue
+		}
+		size, err := strconv.ParseFloat(level.Size, 64)
+		if err != nil || size <= 0 {
+			continue
+		}
+		ledger.prices = append(ledger.prices, price)
+		ledger.remaining = append(ledger.remaining, size)
+	}
+	return ledger
+}
+
+// bestCrossable 返回当前剩余量最高优先级（最先出现，即最优价）且价格与 limitPrice
+// 可以成交的档位的索引，没有可成交档位时返回 ok=false
+func (l *levelLedger) bestCrossable(side string, limitPrice float64) (idx int, ok bool) {
+	for i, price := range l.prices {
+		if l.remaining[i] <= 0 {
+			continue
+		}
+		if side == BUY && price > limitPrice {
+			continue
+		}
+		if side == SELL && price < limitPrice {
+			continue
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+func (l *levelLedger) consume(idx int, size float64) {
+	l.remaining[idx] -= size
+}
+
+// restore 把之前在 price 档位上消耗掉的 size 加回去，用于 rollbackFills 撤销一笔
+// 被拒绝的 FOK 订单时，把它吃掉的对手盘深度还给同一 (tokenID, now) 下后续的撮合
+func (l *levelLedger) restore(price, size float64) {
+	for i, p := range l.prices {
+		if p == price {
+			l.remaining[i] += size
+			return
+		}
+	}
+}
+
+// ledgerFor 返回 tokenID 在模拟时间 t 下共享的剩余深度快照，同一 (tokenID, t) 下的
+// 多次调用复用同一份、按前面调用消耗后的剩余量；t 变化（时钟推进）则重新从
+// BookSource 拉取快照。
+func (b *Backtester) ledgerFor(tokenID string, t time.Time) (*levelLedger, *levelLedger, bool) {
+	if existing, ok := b.ledgers[tokenID]; ok && existing.at.Equal(t) {
+		return existing.ask, existing.bid, true
+	}
+
+	book, ok := b.cfg.Book.Book(tokenID, t)
+	if !ok {
+		return nil, nil, false
+	}
+
+	tl := &tokenLedger{at: t, ask: newLevelLedger(book.Asks), bid: newLevelLedger(book.Bids)}
+	b.ledgers[tokenID] = tl
+	return tl.ask, tl.bid, true
+}
+
+// matchAgainstBook 用 ledgerFor 提供的共享快照撮合一组订单，按价格-时间优先级依次
+// 消耗同一份对手盘档位的剩余量，避免多笔订单（无论是同一批传入的，还是同一模拟时间
+// 下先后几次 submit 传入的）从同一档位凭空重复成交。isMakerFill 为 true 表示这些
+// 订单是此前已挂在盘口、现在才被对手盘吃到（maker 成交）；为 false 表示订单刚提交
+// 就立即吃掉对手盘（taker 成交）。
+func (b *Backtester) matchAgainstBook(tokenID string, orders []*backtestOrder, t time.Time, isMakerFill bool) []*backtestOrder {
+	askLedger, bidLedger, ok := b.ledgerFor(tokenID, t)
+	if !ok {
+		return liveOrders(orders, t)
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		if orders[i].price != orders[j].price {
+			if orders[i].side == BUY {
+				return orders[i].price > orders[j].price
+			}
+			return orders[i].price < orders[j].price
+		}
+		return orders[i].placedAt.Before(orders[j].placedAt)
+	})
+
+	still := make([]*backtestOrder, 0, len(orders))
+	for _, o := range orders {
+		if isExpired(o, t) {
+			continue
+		}
+
+		ledger := askLedger
+		if o.side == SELL {
+			ledger = bidLedger
+		}
+
+		for o.filled < o.size {
+			idx, ok := ledger.bestCrossable(o.side, o.price)
+			if !ok {
+				break
+			}
+
+			fillSize := minFloat(ledger.remaining[idx], o.size-o.filled)
+			if fillSize <= 0 {
+				break
+			}
+
+			ledger.consume(idx, fillSize)
+			b.applyFill(o, ledger.prices[idx], fillSize, t, isMakerFill)
+		}
+
+		if o.filled < o.size {
+			still = append(still, o)
+		}
+	}
+
+	return liveOrders(still, t)
+}
+
+func liveOrders(orders []*backtestOrder, t time.Time) []*backtestOrder {
+	out := make([]*backtestOrder, 0, len(orders))
+	for _, o := range orders {
+		if !isExpired(o, t) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func isExpired(o *backtestOrder, t time.Time) bool {
+	return !o.expires.IsZero() && t.After(o.expires)
+}
+
+// applyFill 结算一笔成交：按 maker/taker 费率计提手续费、更新余额、记录成交并触发回调
+func (b *Backtester) applyFill(o *backtestOrder, price, size float64, t time.Time, isMaker bool) {
+	feeBps := b.cfg.TakerFeeRate
+	if isMaker {
+		feeBps = b.cfg.MakerFeeRate
+	}
+	notional := price * size
+	fee := notional * float64(feeBps) / 10000
+
+	if o.side == BUY {
+		b.balances["USDC"] -= notional + fee
+		b.balances[o.tokenID] += size
+	} else {
+		b.balances["USDC"] += notional - fee
+		b.balances[o.tokenID] -= size
+	}
+
+	o.filled += size
+
+	fill := Fill{
+		TokenID: o.tokenID,
+		Side:    o.side,
+		Price:   price,
+		Size:    size,
+		Fee:     fee,
+		IsMaker: isMaker,
+		Time:    t,
+		Hash:    o.hash,
+	}
+	b.fills = append(b.fills, fill)
+	for _, cb := range b.onFill {
+		cb(fill)
+	}
+}
+
+// rollbackFills 撤销一笔 FOK 订单在本轮撮合中产生的部分成交，并把它消耗掉的
+// 对手盘深度还给共享的 ledger，否则这部分深度会对同一 (tokenID, now) 下后续
+// 提交的订单凭空消失
+func (b *Backtester) rollbackFills(o *backtestOrder) {
+	askLedger, bidLedger, ok := b.ledgerFor(o.tokenID, b.now)
+	ledger := askLedger
+	if o.side == SELL {
+		ledger = bidLedger
+	}
+
+	kept := b.fills[:0:0]
+	for _, f := range b.fills {
+		if f.Hash == o.hash {
+			if f.Side == BUY {
+				b.balances["USDC"] += f.Price*f.Size + f.Fee
+				b.balances[f.TokenID] -= f.Size
+			} else {
+				b.balances["USDC"] -= f.Price*f.Size - f.Fee
+				b.balances[f.TokenID] += f.Size
+			}
+			if ok {
+				ledger.restore(f.Price, f.Size)
+			}
+			continue
+		}
+		kept = append(kept, f)
+	}
+	b.fills = kept
+	o.filled = 0
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
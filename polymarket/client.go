@@ -0,0 +1,10 @@
+package polymarket
+
+// ClobClient 持有风控与回测相关状态。签名器、订单构造器、链 ID 等核心字段由客户端的
+// 初始化逻辑设置，不在这里重复声明。
+type ClobClient struct {
+	riskControllers []RiskController
+
+	// backtester 非 nil 时，下单/撤单路由到内存撮合引擎而不是实盘 API
+	backtester *Backtester
+}
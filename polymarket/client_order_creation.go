@@ -134,6 +134,20 @@ func (c *ClobClient) CreateOrder(orderArgs *OrderArgs, options *PartialCreateOrd
 		}
 	}
 
+	// 风控检查：放在价格、手续费率等经济参数都已解析完成之后，签名之前，
+	// 避免对后续校验会失败的订单提前扣减 MaxDailyNotional 等每日额度
+	if len(c.riskControllers) > 0 {
+		if err := c.runRiskControllers(RiskCheck{
+			TokenID: orderArgs.TokenID,
+			Side:    orderArgs.Side,
+			Price:   orderArgs.Price,
+			Size:    orderArgs.Size,
+			Signer:  c.signer.Address(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	// 构建OrderData
 	taker := orderArgs.Taker
 	if taker == "" {
@@ -163,6 +177,11 @@ func (c *ClobClient) CreateOrder(orderArgs *OrderArgs, options *PartialCreateOrd
 		return nil, err
 	}
 
+	// 回测模式：暂存下单参数，供随后的 SubmitOrder 调用路由到内存撮合引擎
+	if c.backtester != nil {
+		c.backtester.stage(signedOrder.Hash(), orderArgs.TokenID, orderArgs.Side, orderArgs.Price, orderArgs.Size, orderArgs.Expiration)
+	}
+
 	return signedOrder, nil
 }
 
@@ -233,6 +252,20 @@ func (c *ClobClient) CreateMarketOrder(orderArgs *MarketOrderArgs, options *Part
 		return nil, err
 	}
 
+	// 风控检查：放在价格、手续费率等经济参数都已解析完成之后，签名之前，
+	// 避免对后续校验会失败的订单提前扣减 MaxDailyNotional 等每日额度
+	if len(c.riskControllers) > 0 {
+		if err := c.runRiskControllers(RiskCheck{
+			TokenID: orderArgs.TokenID,
+			Side:    orderArgs.Side,
+			Price:   orderArgs.Price,
+			Size:    orderArgs.Amount / orderArgs.Price,
+			Signer:  c.signer.Address(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	// 构建OrderData
 	taker := orderArgs.Taker
 	if taker == "" {
@@ -262,6 +295,12 @@ func (c *ClobClient) CreateMarketOrder(orderArgs *MarketOrderArgs, options *Part
 		return nil, err
 	}
 
+	// 回测模式：暂存下单参数，供随后的 SubmitOrder 调用路由到内存撮合引擎
+	if c.backtester != nil {
+		size := orderArgs.Amount / orderArgs.Price
+		c.backtester.stage(signedOrder.Hash(), orderArgs.TokenID, orderArgs.Side, orderArgs.Price, size, 0)
+	}
+
 	return signedOrder, nil
 }
 
@@ -279,9 +318,30 @@ func (c *ClobClient) CreateAndPostOrder(orderArgs *OrderArgs, options *PartialCr
 		orderType = *options.OrderType
 	}
 
+	// SubmitOrder 内部会在回测模式下自动路由到内存撮合引擎
+	return c.SubmitOrder(order, orderType)
+}
+
+// SubmitOrder 提交一笔已签名订单。回测模式下路由到内存撮合引擎（要求该订单此前
+// 由 CreateOrder/CreateMarketOrder 暂存过下单参数），否则调用实盘的 PostOrder。
+// 调用方（包括 CreateAndPostOrder 与直接持有 *SignedOrder 的外部包）都应该
+// 经过这里提交订单，而不是直接调用 PostOrder，以免绕开回测路由。
+func (c *ClobClient) SubmitOrder(order *SignedOrder, orderType OrderType) (interface{}, error) {
+	if c.backtester != nil {
+		return c.backtester.submitPending(order.Hash(), orderType)
+	}
 	return c.PostOrder(order, orderType)
 }
 
+// CancelPendingOrder 撤销一笔挂单。回测模式下从内存撮合引擎的挂单队列中移除，
+// 否则调用实盘的 CancelOrder。
+func (c *ClobClient) CancelPendingOrder(hash string) error {
+	if c.backtester != nil {
+		return c.backtester.cancel(hash)
+	}
+	return c.CancelOrder(hash)
+}
+
 // CalculateMarketPrice 计算市价
 func (c *ClobClient) CalculateMarketPrice(tokenID, side string, amount float64, orderType OrderType) (float64, error) {
 	book, err := c.GetOrderBook(tokenID)
@@ -0,0 +1,271 @@
+// Package liquidity 实现一个分层做市（layered market making）子系统，
+// 在 ClobClient.CreateOrder/SubmitOrder 之上持续为单个 outcome token 的双边挂单。
+package liquidity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xNetuser/Polymarket-golang/polymarket"
+)
+
+// Scale 描述一条挂单梯子内层与层之间的尺寸分布方式
+type Scale string
+
+const (
+	// ScaleLinear 各层等权重分布
+	ScaleLinear Scale = "linear"
+	// ScaleExp 指数分布，内层（更接近参考价）占比更大
+	ScaleExp Scale = "exp"
+)
+
+// Config 做市子系统配置
+type Config struct {
+	TokenID string
+
+	// NumLayers 单边挂单层数
+	NumLayers int
+	// BidAmount/AskAmount 每边投入的 USDC 总量，按 Scale 分摊到各层
+	BidAmount float64
+	AskAmount float64
+	// PriceRange 距参考价的最大偏离比例（如 0.1 代表 10%）
+	PriceRange float64
+	// Spread 买卖两侧合计的最小价差比例，每侧最内层距参考价 Spread/2
+	Spread float64
+	// Scale 控制各层尺寸的分布函数
+	Scale Scale
+	// ScaleDomain/ScaleRange 供 exp 缩放使用的输入/输出值域，留空时分别使用默认 [0,1] 与 [1,0.1]
+	ScaleDomain [2]float64
+	ScaleRange  [2]float64
+
+	// RefreshInterval 重新报价的节拍
+	RefreshInterval time.Duration
+	// MaxExposure 该 token 允许承担的最大净敞口（份数），用于裁剪层数
+	MaxExposure float64
+	// MinProfit 新的 mid 与上次报价 mid 的最小变动阈值，小于该值则跳过本次重新报价
+	MinProfit float64
+
+	// TickSize 若为空则通过 ClobClient 解析市场最小跳动
+	TickSize *polymarket.TickSize
+}
+
+// RefPriceFunc 返回参考价（如最新成交价或 mid），由调用方提供
+type RefPriceFunc func(ctx context.Context, tokenID string) (float64, error)
+
+// ExposureFunc 返回当前持仓/余额口径下该 token 的净敞口（正数表示净多头份数）
+type ExposureFunc func(ctx context.Context, tokenID string) (float64, error)
+
+// Engine 是分层做市子系统的运行实例
+type Engine struct {
+	client *polymarket.ClobClient
+	cfg    Config
+
+	refPrice RefPriceFunc
+	exposure ExposureFunc
+
+	book *polymarket.ActiveOrderBook
+
+	mu       sync.Mutex
+	lastMid  float64
+	haveMid  bool
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewEngine 构造做市子系统。book 应由调用方通过 polymarket.NewActiveOrderBook
+// 构造并 Subscribe 到用户数据 WebSocket 流，这样部分成交才会真正反映到
+// UntouchedHashes，引擎每轮刷新才只会撤销尚未成交的层。
+func NewEngine(client *polymarket.ClobClient, cfg Config, book *polymarket.ActiveOrderBook, refPrice RefPriceFunc, exposure ExposureFunc) (*Engine, error) {
+	if cfg.NumLayers <= 0 {
+		return nil, fmt.Errorf("liquidity: NumLayers must be positive")
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = 5 * time.Second
+	}
+	if cfg.Scale == "" {
+		cfg.Scale = ScaleLinear
+	}
+
+	return &Engine{
+		client:   client,
+		cfg:      cfg,
+		refPrice: refPrice,
+		exposure: exposure,
+		book:     book,
+		stopCh:   make(chan struct{}),
+	}, nil
+}
+
+// Run 启动刷新循环，阻塞直到 ctx 被取消或 Stop 被调用
+func (e *Engine) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-e.stopCh:
+			return nil
+		case <-ticker.C:
+			if err := e.refresh(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Stop 结束刷新循环（不撤销已挂的订单，调用方应自行决定是否清仓）
+func (e *Engine) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+	})
+}
+
+// refresh 执行一次完整的重新报价：计算梯子 -> 裁剪敞口 -> 撤销未成交旧单 -> 提交新单
+func (e *Engine) refresh(ctx context.Context) error {
+	ref, err := e.refPrice(ctx, e.cfg.TokenID)
+	if err != nil {
+		return fmt.Errorf("liquidity: ref price: %w", err)
+	}
+
+	e.mu.Lock()
+	if e.haveMid && e.cfg.MinProfit > 0 {
+		if absFloat(ref-e.lastMid) < e.cfg.MinProfit {
+			e.mu.Unlock()
+			return nil
+		}
+	}
+	e.mu.Unlock()
+
+	tickSize, err := e.resolveTickSize()
+	if err != nil {
+		return err
+	}
+
+	bidLayers := e.buildLayers(ctx, polymarket.BUY, ref, e.cfg.BidAmount, tickSize)
+	askLayers := e.buildLayers(ctx, polymarket.SELL, ref, e.cfg.AskAmount, tickSize)
+
+	if e.exposure != nil && e.cfg.MaxExposure > 0 {
+		exp, err := e.exposure(ctx, e.cfg.TokenID)
+		if err != nil {
+			return fmt.Errorf("liquidity: exposure: %w", err)
+		}
+		bidLayers = trimForExposure(bidLayers, e.cfg.MaxExposure-exp)
+		askLayers = trimForExposure(askLayers, e.cfg.MaxExposure+exp)
+	}
+
+	stale := e.book.UntouchedHashes(e.cfg.TokenID)
+	for _, h := range stale {
+		if err := e.client.CancelPendingOrder(h); err != nil {
+			return fmt.Errorf("liquidity: cancel stale order %s: %w", h, err)
+		}
+		e.book.Remove(h)
+	}
+
+	for _, layer := range append(bidLayers, askLayers...) {
+		args := &polymarket.OrderArgs{
+			TokenID: e.cfg.TokenID,
+			Side:    layer.side,
+			Price:   layer.price,
+			Size:    layer.size,
+		}
+		options := &polymarket.PartialCreateOrderOptions{TickSize: &tickSize}
+		order, err := e.client.CreateOrder(args, options)
+		if err != nil {
+			return fmt.Errorf("liquidity: create order: %w", err)
+		}
+		if _, err := e.client.SubmitOrder(order, polymarket.OrderTypeGTC); err != nil {
+			return fmt.Errorf("liquidity: post order: %w", err)
+		}
+		e.book.Track(order, e.cfg.TokenID, layer.side, layer.price, layer.size)
+	}
+
+	e.mu.Lock()
+	e.lastMid = ref
+	e.haveMid = true
+	e.mu.Unlock()
+
+	return nil
+}
+
+func (e *Engine) resolveTickSize() (polymarket.TickSize, error) {
+	if e.cfg.TickSize != nil {
+		return *e.cfg.TickSize, nil
+	}
+	return e.client.GetTickSize(e.cfg.TokenID)
+}
+
+type layer struct {
+	side  string
+	price float64
+	size  float64
+}
+
+// buildLayers 按配置的 spread/range 生成一侧的价格梯子，并按 Scale 分摊总量
+func (e *Engine) buildLayers(ctx context.Context, side string, ref, totalAmount float64, tickSize polymarket.TickSize) []layer {
+	if totalAmount <= 0 || e.cfg.NumLayers <= 0 {
+		return nil
+	}
+
+	weights := scaleWeights(e.cfg.Scale, e.cfg.NumLayers, e.cfg.ScaleDomain, e.cfg.ScaleRange)
+	layers := make([]layer, 0, e.cfg.NumLayers)
+
+	for i := 0; i < e.cfg.NumLayers; i++ {
+		frac := e.cfg.Spread / 2
+		if e.cfg.NumLayers > 1 {
+			frac += (e.cfg.PriceRange - e.cfg.Spread/2) * float64(i) / float64(e.cfg.NumLayers-1)
+		}
+
+		var price float64
+		if side == polymarket.BUY {
+			price = ref * (1 - frac)
+		} else {
+			price = ref * (1 + frac)
+		}
+		price = roundToTick(price, tickSize, side)
+		if price <= 0 || price >= 1 {
+			continue
+		}
+
+		amount := totalAmount * weights[i]
+		if amount <= 0 {
+			continue
+		}
+
+		layers = append(layers, layer{side: side, price: price, size: amount / price})
+	}
+
+	return layers
+}
+
+// trimForExposure 按剩余可承受敞口裁剪层。exp 缩放下内层（更贴近参考价）权重更大、
+// size 也更大，因此优先保留 size 更大的层，先丢弃外层这些次要的小额报价。
+func trimForExposure(layers []layer, remaining float64) []layer {
+	if remaining <= 0 {
+		return nil
+	}
+
+	sort.SliceStable(layers, func(i, j int) bool { return layers[i].size > layers[j].size })
+
+	kept := make([]layer, 0, len(layers))
+	used := 0.0
+	for _, l := range layers {
+		if used+l.size > remaining {
+			continue
+		}
+		used += l.size
+		kept = append(kept, l)
+	}
+	return kept
+}
+
+func absFloat(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package liquidity
+
+import (
+	"testing"
+
+	"github.com/0xNetuser/Polymarket-golang/polymarket"
+)
+
+func TestRoundToTickNeverNarrowsTheSpread(t *testing.T) {
+	if got := roundToTick(0.505, polymarket.TickSize("0.01"), polymarket.SELL); got != 0.51 {
+		t.Fatalf("expected ask to round up away from the reference price, got %f", got)
+	}
+	if got := roundToTick(0.495, polymarket.TickSize("0.01"), polymarket.BUY); got != 0.49 {
+		t.Fatalf("expected bid to round down away from the reference price, got %f", got)
+	}
+}
+
+func TestScaleWeightsExpFavorsInnerLayers(t *testing.T) {
+	weights := scaleWeights(ScaleExp, 4, [2]float64{}, [2]float64{})
+
+	for i := 1; i < len(weights); i++ {
+		if weights[i] >= weights[i-1] {
+			t.Fatalf("expected strictly decreasing weights from inner to outer layer, got %v", weights)
+		}
+	}
+
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	if sum < 0.999 || sum > 1.001 {
+		t.Fatalf("expected weights to sum to 1, got %f", sum)
+	}
+}
+
+func TestScaleWeightsLinearAreEqual(t *testing.T) {
+	weights := scaleWeights(ScaleLinear, 3, [2]float64{}, [2]float64{})
+	for _, w := range weights {
+		if w != weights[0] {
+			t.Fatalf("expected equal weights for linear scale, got %v", weights)
+		}
+	}
+}
+
+func TestTrimForExposureKeepsLargestLayersFirst(t *testing.T) {
+	layers := []layer{
+		{side: "BUY", price: 0.5, size: 10},
+		{side: "BUY", price: 0.4, size: 100},
+		{side: "BUY", price: 0.3, size: 1},
+	}
+
+	kept := trimForExposure(layers, 105)
+
+	found := false
+	for _, l := range kept {
+		if l.size == 100 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the largest layer to be preserved under exposure pressure, got %v", kept)
+	}
+
+	total := 0.0
+	for _, l := range kept {
+		total += l.size
+	}
+	if total > 105 {
+		t.Fatalf("kept layers exceed remaining exposure budget: %f", total)
+	}
+}
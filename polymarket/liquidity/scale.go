@@ -0,0 +1,60 @@
+package liquidity
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/0xNetuser/Polymarket-golang/polymarket"
+)
+
+// scaleWeights 返回 n 层的归一化权重（和为 1），按 Scale 指定的分布函数计算。
+// 外层（距离参考价更远）在 exp 模式下权重更小，因为做市希望把资金集中在内层。
+func scaleWeights(scale Scale, n int, domain, rng [2]float64) []float64 {
+	weights := make([]float64, n)
+
+	switch scale {
+	case ScaleExp:
+		lo, hi := domain[0], domain[1]
+		if lo == 0 && hi == 0 {
+			lo, hi = 0, 1
+		}
+		rlo, rhi := rng[0], rng[1]
+		if rlo == 0 && rhi == 0 {
+			rlo, rhi = 1, 0.1
+		}
+
+		sum := 0.0
+		for i := 0; i < n; i++ {
+			t := lo
+			if n > 1 {
+				t = lo + (hi-lo)*float64(i)/float64(n-1)
+			}
+			w := rlo * math.Pow(rhi/rlo, (t-lo)/(hi-lo))
+			weights[i] = w
+			sum += w
+		}
+		for i := range weights {
+			weights[i] /= sum
+		}
+	default: // ScaleLinear
+		for i := range weights {
+			weights[i] = 1.0 / float64(n)
+		}
+	}
+
+	return weights
+}
+
+// roundToTick 将价格舍入到合法的 tick size 档位，方向取决于 side：bid 向下舍入、
+// ask 向上舍入，这样报价永远不会比配置的 Spread/2 更贴近参考价（向内舍入会吃掉
+// 本该保留的最小价差）。
+func roundToTick(price float64, tickSize polymarket.TickSize, side string) float64 {
+	step, err := strconv.ParseFloat(string(tickSize), 64)
+	if err != nil || step <= 0 {
+		return price
+	}
+	if side == polymarket.SELL {
+		return math.Ceil(price/step) * step
+	}
+	return math.Floor(price/step) * step
+}
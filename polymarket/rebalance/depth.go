@@ -0,0 +1,40 @@
+package rebalance
+
+import (
+	"strconv"
+
+	"github.com/0xNetuser/Polymarket-golang/polymarket"
+)
+
+// topOfBookMid 返回最优买一/卖一的中间价，任意一侧为空则返回 false
+func topOfBookMid(book *polymarket.OrderBook) (float64, bool) {
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return 0, false
+	}
+
+	bid := parseLevelPrice(book.Bids[0])
+	ask := parseLevelPrice(book.Asks[0])
+	if bid <= 0 || ask <= 0 {
+		return 0, false
+	}
+
+	return (bid + ask) / 2, true
+}
+
+// parseLevelPrice 解析订单簿档位的价格字符串
+func parseLevelPrice(level polymarket.OrderSummary) float64 {
+	price, err := strconv.ParseFloat(level.Price, 64)
+	if err != nil {
+		return 0
+	}
+	return price
+}
+
+// parseLevelSize 解析订单簿档位的份数字符串
+func parseLevelSize(level polymarket.OrderSummary) float64 {
+	size, err := strconv.ParseFloat(level.Size, 64)
+	if err != nil {
+		return 0
+	}
+	return size
+}
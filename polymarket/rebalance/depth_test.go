@@ -0,0 +1,18 @@
+package rebalance
+
+import (
+	"testing"
+
+	"github.com/0xNetuser/Polymarket-golang/polymarket"
+)
+
+func TestParseLevelSizeOnlyReadsTheGivenLevel(t *testing.T) {
+	levels := []polymarket.OrderSummary{
+		{Price: "0.50", Size: "10"},
+		{Price: "0.51", Size: "1000"},
+	}
+
+	if got := parseLevelSize(levels[0]); got != 10 {
+		t.Fatalf("expected top level size 10, got %f", got)
+	}
+}
@@ -0,0 +1,181 @@
+// Package rebalance 实现一个跨 outcome token 的组合再平衡策略，
+// 按目标权重与当前持仓的偏离量生成 ClobClient.CreateOrder 所需的批量订单。
+package rebalance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xNetuser/Polymarket-golang/polymarket"
+)
+
+// HoldingsFunc 返回当前持仓，单位为份数，按 tokenID 索引
+type HoldingsFunc func(ctx context.Context, tokenIDs []string) (map[string]float64, error)
+
+// Options 配置再平衡行为
+type Options struct {
+	// QuoteBudget 本次再平衡可用的 USDC 总预算
+	QuoteBudget float64
+	// MinDeviation 低于该偏离比例的 token 不会被调整
+	MinDeviation float64
+	// DryRun 为 true 时只返回计划订单，不调用 CreateOrder 提交
+	DryRun bool
+	// OnStart 在 Run 开始时、首次调度之前调用一次
+	OnStart func()
+	// Interval 周期性调度的间隔，Run 会按此间隔重复调用 Rebalance
+	Interval time.Duration
+}
+
+// PlannedOrder 描述一笔为缩小偏离而生成的订单及其触发原因
+type PlannedOrder struct {
+	polymarket.OrderArgs
+	TokenID      string
+	TargetWeight float64
+	Deviation    float64
+}
+
+// RebalanceEngine 绑定到一个 ClobClient，按目标权重驱动组合再平衡
+type RebalanceEngine struct {
+	client   *polymarket.ClobClient
+	opts     Options
+	holdings HoldingsFunc
+}
+
+// NewEngine 构造再平衡引擎
+func NewEngine(client *polymarket.ClobClient, holdings HoldingsFunc, opts Options) *RebalanceEngine {
+	return &RebalanceEngine{client: client, opts: opts, holdings: holdings}
+}
+
+// Rebalance 计算每个 token 相对目标权重的偏离，生成并（非 dryRun 时）提交订单。
+// targets 中的权重之和应为 1.0，调用方负责保证。
+func (e *RebalanceEngine) Rebalance(ctx context.Context, targets map[string]float64) ([]PlannedOrder, error) {
+	tokenIDs := make([]string, 0, len(targets))
+	for tokenID := range targets {
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+
+	holdings, err := e.holdings(ctx, tokenIDs)
+	if err != nil {
+		return nil, fmt.Errorf("rebalance: holdings: %w", err)
+	}
+
+	var planned []PlannedOrder
+	for tokenID, weight := range targets {
+		book, err := e.client.GetOrderBook(tokenID)
+		if err != nil {
+			return nil, fmt.Errorf("rebalance: order book for %s: %w", tokenID, err)
+		}
+
+		mid, ok := topOfBookMid(book)
+		if !ok {
+			continue
+		}
+
+		currentValue := holdings[tokenID] * mid
+		targetValue := weight * e.opts.QuoteBudget
+		deviationValue := targetValue - currentValue
+		deviation := deviationValue / e.opts.QuoteBudget
+
+		if absFloat(deviation) < e.opts.MinDeviation {
+			continue
+		}
+
+		order, ok := e.buildOrder(tokenID, book, deviationValue, weight, deviation)
+		if !ok {
+			continue
+		}
+		planned = append(planned, order)
+	}
+
+	if e.opts.DryRun {
+		return planned, nil
+	}
+
+	for _, order := range planned {
+		args := order.OrderArgs
+		if _, err := e.client.CreateAndPostOrder(&args, nil); err != nil {
+			return planned, fmt.Errorf("rebalance: submit order for %s: %w", order.TokenID, err)
+		}
+	}
+
+	return planned, nil
+}
+
+// buildOrder 决定 BUY/SELL 方向，并按可用的对手盘深度裁剪订单规模
+func (e *RebalanceEngine) buildOrder(tokenID string, book *polymarket.OrderBook, deviationValue, weight, deviation float64) (PlannedOrder, bool) {
+	side := polymarket.BUY
+	levels := book.Asks
+	if deviationValue < 0 {
+		side = polymarket.SELL
+		levels = book.Bids
+		deviationValue = -deviationValue
+	}
+
+	if len(levels) == 0 {
+		return PlannedOrder{}, false
+	}
+
+	price := parseLevelPrice(levels[0])
+	if price <= 0 {
+		return PlannedOrder{}, false
+	}
+
+	// 限价单只挂在最优价，只有恰好挂在这一档的量才可能成交；更差价格的深度对
+	// 这一笔订单不可用，不能把整本的聚合深度当作可成交量。
+	wantSize := deviationValue / price
+	available := parseLevelSize(levels[0])
+	size := wantSize
+	if size > available {
+		size = available
+	}
+	if size <= 0 {
+		return PlannedOrder{}, false
+	}
+
+	return PlannedOrder{
+		OrderArgs: polymarket.OrderArgs{
+			TokenID: tokenID,
+			Side:    side,
+			Price:   price,
+			Size:    size,
+		},
+		TokenID:      tokenID,
+		TargetWeight: weight,
+		Deviation:    deviation,
+	}, true
+}
+
+// Run 按 Options.Interval 周期性调用 Rebalance，直到 ctx 被取消
+func (e *RebalanceEngine) Run(ctx context.Context, targets map[string]float64) error {
+	if e.opts.OnStart != nil {
+		e.opts.OnStart()
+	}
+
+	interval := e.opts.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.Rebalance(ctx, targets); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func absFloat(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,157 @@
+package polymarket
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrRiskLimitExceeded 是所有风控拒单的哨兵错误，可用 errors.Is 判断
+var ErrRiskLimitExceeded = errors.New("risk limit exceeded")
+
+// RiskCheck 描述一笔即将签名的订单，供 RiskController 评估
+type RiskCheck struct {
+	TokenID string
+	Side    string
+	Price   float64
+	Size    float64
+	Signer  string
+}
+
+// Notional 返回该笔订单的 USDC 名义金额
+func (r RiskCheck) Notional() float64 {
+	return r.Price * r.Size
+}
+
+// RiskController 在订单签名前接受检查，返回非 nil 错误将阻止下单。
+// CreateOrder/CreateMarketOrder 会按 AddRiskController 注册顺序依次调用所有控制器。
+type RiskController interface {
+	CheckOrder(c *ClobClient, check RiskCheck) error
+}
+
+// AddRiskController 注册一个风控规则，支持叠加多个控制器（如自定义熔断器）
+func (c *ClobClient) AddRiskController(rc RiskController) {
+	c.riskControllers = append(c.riskControllers, rc)
+}
+
+// runRiskControllers 依次执行已注册的风控规则，第一个拒绝即短路返回
+func (c *ClobClient) runRiskControllers(check RiskCheck) error {
+	for _, rc := range c.riskControllers {
+		if err := rc.CheckOrder(c, check); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BasicRiskController 是默认的下单前风控实现，覆盖余额、单笔金额、持仓、挂单数量
+// 以及按签名地址统计的每日累计名义金额限制。
+type BasicRiskController struct {
+	MinQuoteBalance       float64
+	MaxOrderAmount        float64
+	MaxPositionPerToken   float64
+	MaxOpenOrdersPerToken int
+	MaxDailyNotional      float64
+
+	// AllowList 非空时，只有在列表中的 token 才允许下单
+	AllowList map[string]bool
+	// DenyList 中的 token 一律拒绝，优先级高于 AllowList
+	DenyList map[string]bool
+
+	mu            sync.Mutex
+	dailyDate     string
+	dailyNotional map[string]float64 // 按签名地址累计
+}
+
+// NewBasicRiskController 构造默认风控控制器
+func NewBasicRiskController() *BasicRiskController {
+	return &BasicRiskController{dailyNotional: make(map[string]float64)}
+}
+
+// CheckOrder 实现 RiskController
+func (r *BasicRiskController) CheckOrder(c *ClobClient, check RiskCheck) error {
+	if r.DenyList != nil && r.DenyList[check.TokenID] {
+		return fmt.Errorf("%w: token %s is on the deny list", ErrRiskLimitExceeded, check.TokenID)
+	}
+	if len(r.AllowList) > 0 && !r.AllowList[check.TokenID] {
+		return fmt.Errorf("%w: token %s is not on the allow list", ErrRiskLimitExceeded, check.TokenID)
+	}
+
+	notional := check.Notional()
+
+	if r.MaxOrderAmount > 0 && notional > r.MaxOrderAmount {
+		return fmt.Errorf("%w: order notional %.2f exceeds max order amount %.2f", ErrRiskLimitExceeded, notional, r.MaxOrderAmount)
+	}
+
+	if r.MinQuoteBalance > 0 && check.Side == BUY {
+		balance, err := c.GetBalanceAllowance(check.TokenID)
+		if err != nil {
+			return fmt.Errorf("risk controller: balance: %w", err)
+		}
+		if balance-notional < r.MinQuoteBalance {
+			return fmt.Errorf("%w: remaining balance would drop below min quote balance %.2f", ErrRiskLimitExceeded, r.MinQuoteBalance)
+		}
+	}
+
+	if r.MaxPositionPerToken > 0 {
+		position, err := c.GetPosition(check.TokenID)
+		if err != nil {
+			return fmt.Errorf("risk controller: position: %w", err)
+		}
+		projected := position
+		if check.Side == BUY {
+			projected += check.Size
+		} else {
+			projected -= check.Size
+		}
+		if absFloat(projected) > r.MaxPositionPerToken {
+			return fmt.Errorf("%w: position in %s would reach %.2f, max is %.2f", ErrRiskLimitExceeded, check.TokenID, projected, r.MaxPositionPerToken)
+		}
+	}
+
+	if r.MaxOpenOrdersPerToken > 0 {
+		open, err := c.GetOpenOrders(check.TokenID)
+		if err != nil {
+			return fmt.Errorf("risk controller: open orders: %w", err)
+		}
+		if len(open) >= r.MaxOpenOrdersPerToken {
+			return fmt.Errorf("%w: token %s already has %d open orders, max is %d", ErrRiskLimitExceeded, check.TokenID, len(open), r.MaxOpenOrdersPerToken)
+		}
+	}
+
+	if r.MaxDailyNotional > 0 {
+		if err := r.chargeDailyNotional(check.Signer, notional); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chargeDailyNotional 累加签名地址当日的名义金额，超限时拒绝并回滚本次累加
+func (r *BasicRiskController) chargeDailyNotional(signer string, notional float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	today := time.Now().UTC().Format("2006-01-02")
+	if r.dailyDate != today {
+		r.dailyDate = today
+		r.dailyNotional = make(map[string]float64)
+	}
+
+	next := r.dailyNotional[signer] + notional
+	if next > r.MaxDailyNotional {
+		return fmt.Errorf("%w: signer %s daily notional would reach %.2f, max is %.2f", ErrRiskLimitExceeded, signer, next, r.MaxDailyNotional)
+	}
+
+	r.dailyNotional[signer] = next
+	return nil
+}
+
+func absFloat(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,44 @@
+package strategy
+
+// CCI 在预测市场的价格序列上增量计算顺势指标（Commodity Channel Index）。
+// Polymarket 没有逐笔的高/低/收盘价，这里用买一卖一中间价 mid 替代传统的
+// typical price：CCI = (TP - SMA(TP, n)) / (0.015 * MeanAbsDev(TP, n))。
+type CCI struct {
+	window int
+	buf    *ring
+	value  float64
+	ready  bool
+}
+
+// NewCCI 构造一个窗口长度为 window 的 CCI 流
+func NewCCI(window int) *CCI {
+	return &CCI{window: window, buf: newRing(window)}
+}
+
+// Update 写入一个新的 mid 价并重新计算 CCI 值
+func (c *CCI) Update(mid float64) {
+	c.buf.push(mid)
+	if !c.buf.full() {
+		c.ready = false
+		return
+	}
+
+	sma := c.buf.mean()
+	mad := c.buf.meanAbsDev(sma)
+	if mad == 0 {
+		c.value = 0
+	} else {
+		c.value = (mid - sma) / (0.015 * mad)
+	}
+	c.ready = true
+}
+
+// Value 返回最近一次计算的 CCI 值
+func (c *CCI) Value() float64 {
+	return c.value
+}
+
+// Ready 指示窗口是否已填满，CCI 值是否有统计意义
+func (c *CCI) Ready() bool {
+	return c.ready
+}
@@ -0,0 +1,90 @@
+package strategy
+
+import "time"
+
+// bar 是按固定 Interval 切割出的一根 K 线（只有高低价，mid 序列没有开收盘概念上的区别）
+type bar struct {
+	start      time.Time
+	high, low  float64
+	rangeKnown bool
+}
+
+func (b *bar) extend(mid float64) {
+	if !b.rangeKnown {
+		b.high, b.low = mid, mid
+		b.rangeKnown = true
+		return
+	}
+	if mid > b.high {
+		b.high = mid
+	}
+	if mid < b.low {
+		b.low = mid
+	}
+}
+
+func (b *bar) rng() float64 {
+	return b.high - b.low
+}
+
+// NR4 把价格序列按 Interval 分桶成 K 线，并在当前 K 线的高低价区间是最近
+// 4 根中最窄时发出信号（narrowest range of the last 4 bars）。
+type NR4 struct {
+	interval time.Duration
+	closed   []*bar // 最近已收盘的 K 线，最多保留 4 根
+	current  *bar
+	signal   bool
+}
+
+// NewNR4 构造一个按 interval 分桶的 NR4 流
+func NewNR4(interval time.Duration) *NR4 {
+	return &NR4{interval: interval}
+}
+
+// Update 写入一个新的 tick（mid 价，时间戳 t），在跨越 bar 边界时收盘上一根并重新判定信号
+func (n *NR4) Update(mid float64, t time.Time) (barClosed bool) {
+	if n.current == nil {
+		n.current = &bar{start: t.Truncate(n.interval)}
+	}
+
+	barStart := t.Truncate(n.interval)
+	if barStart.After(n.current.start) {
+		n.closeCurrent()
+		n.current = &bar{start: barStart}
+		barClosed = true
+	}
+
+	n.current.extend(mid)
+	n.evaluateSignal(n.current)
+	return barClosed
+}
+
+func (n *NR4) closeCurrent() {
+	n.closed = append(n.closed, n.current)
+	if len(n.closed) > 4 {
+		n.closed = n.closed[len(n.closed)-4:]
+	}
+}
+
+// evaluateSignal 判断 candidate（可能是尚未收盘的当前 bar）的区间是否比最近 3 根已收盘 bar 都窄
+func (n *NR4) evaluateSignal(candidate *bar) {
+	if len(n.closed) < 3 {
+		n.signal = false
+		return
+	}
+
+	recent := n.closed[len(n.closed)-3:]
+	narrowest := candidate.rng()
+	for _, b := range recent {
+		if b.rng() < narrowest {
+			n.signal = false
+			return
+		}
+	}
+	n.signal = true
+}
+
+// Signal 指示当前 bar 是否为最近 4 根中区间最窄的（NR4）
+func (n *NR4) Signal() bool {
+	return n.signal
+}
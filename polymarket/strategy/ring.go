@@ -0,0 +1,52 @@
+package strategy
+
+// ring 是一个定长的环形缓冲区，用于增量维护 CCI 指标的滑动窗口。
+type ring struct {
+	values []float64
+	size   int
+	filled bool
+	pos    int
+}
+
+func newRing(size int) *ring {
+	return &ring{values: make([]float64, size), size: size}
+}
+
+// push 写入一个新值，覆盖最旧的数据
+func (r *ring) push(v float64) {
+	r.values[r.pos] = v
+	r.pos = (r.pos + 1) % r.size
+	if r.pos == 0 {
+		r.filled = true
+	}
+}
+
+// full 窗口是否已被填满，未填满前指标不具有统计意义
+func (r *ring) full() bool {
+	return r.filled
+}
+
+// mean 返回窗口内的算术平均
+func (r *ring) mean() float64 {
+	sum := 0.0
+	for _, v := range r.values {
+		sum += v
+	}
+	return sum / float64(r.size)
+}
+
+// meanAbsDev 返回窗口内相对 mean 的平均绝对偏差
+func (r *ring) meanAbsDev(mean float64) float64 {
+	sum := 0.0
+	for _, v := range r.values {
+		sum += absFloat(v - mean)
+	}
+	return sum / float64(r.size)
+}
+
+func absFloat(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,77 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRingNotFullBeforeSizePushes(t *testing.T) {
+	r := newRing(3)
+	r.push(1)
+	r.push(2)
+	if r.full() {
+		t.Fatalf("expected ring not full after 2 pushes into size-3 ring")
+	}
+	r.push(3)
+	if !r.full() {
+		t.Fatalf("expected ring full after 3 pushes into size-3 ring")
+	}
+}
+
+func TestRingMeanAndMeanAbsDev(t *testing.T) {
+	r := newRing(4)
+	for _, v := range []float64{1, 2, 3, 4} {
+		r.push(v)
+	}
+
+	if mean := r.mean(); mean != 2.5 {
+		t.Fatalf("expected mean 2.5, got %f", mean)
+	}
+
+	if mad := r.meanAbsDev(2.5); mad != 1 {
+		t.Fatalf("expected mean abs dev 1, got %f", mad)
+	}
+}
+
+func TestCCINotReadyUntilWindowFull(t *testing.T) {
+	c := NewCCI(3)
+	c.Update(0.5)
+	c.Update(0.5)
+	if c.Ready() {
+		t.Fatalf("expected CCI not ready before window is full")
+	}
+	c.Update(0.5)
+	if !c.Ready() {
+		t.Fatalf("expected CCI ready once window is full")
+	}
+	if c.Value() != 0 {
+		t.Fatalf("expected CCI value 0 for a constant series, got %f", c.Value())
+	}
+}
+
+func TestNR4SignalsOnlyWhenCurrentBarIsNarrowest(t *testing.T) {
+	n := NewNR4(time.Minute)
+	base := time.Unix(0, 0)
+
+	// 前 3 根 bar 的区间依次为 1.0, 0.5, 0.2
+	n.Update(0.0, base)
+	n.Update(1.0, base)
+	n.Update(0.0, base.Add(time.Minute))
+	n.Update(0.5, base.Add(time.Minute))
+	n.Update(0.0, base.Add(2*time.Minute))
+	n.Update(0.2, base.Add(2*time.Minute))
+
+	// 第 4 根 bar 的区间 0.1，比前 3 根都窄 -> 应该触发信号
+	n.Update(0.0, base.Add(3*time.Minute))
+	n.Update(0.1, base.Add(3*time.Minute))
+	if !n.Signal() {
+		t.Fatalf("expected NR4 signal when current bar is narrowest of last 4")
+	}
+
+	// 第 5 根 bar 的区间 0.3，比最近 3 根收盘 bar（0.5/0.2/0.1）中的 0.2 和 0.1 宽 -> 不应触发
+	n.Update(0.0, base.Add(4*time.Minute))
+	n.Update(0.3, base.Add(4*time.Minute))
+	if n.Signal() {
+		t.Fatalf("expected no NR4 signal when current bar is not the narrowest")
+	}
+}
@@ -0,0 +1,230 @@
+// Package strategy 提供一个基于 CCI/NR4 指标的自动交易策略，
+// 把指标信号转换为 ClobClient.CreateOrder 调用，并在入场单实际成交后才挂出止盈止损。
+package strategy
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/0xNetuser/Polymarket-golang/polymarket"
+)
+
+// Config 配置自动交易策略
+type Config struct {
+	// TokenID 是做多方向（YES）使用的 token，ComplementTokenID 是其互补的 NO token
+	TokenID           string
+	ComplementTokenID string
+
+	Window      int           // CCI 窗口长度
+	BarInterval time.Duration // NR4 分桶间隔
+
+	LongThreshold  float64 // CCI < -LongThreshold 且 NR4 信号成立时触发做多
+	ShortThreshold float64 // CCI > ShortThreshold 时触发做空（买入 NO）
+
+	Amount   float64 // 每次开仓投入的 USDC
+	Leverage float64 // 对 Amount 的放大倍数
+
+	ProfitRange float64 // 止盈相对入场价的价差
+	LossRange   float64 // 止损相对入场价的价差
+
+	// StrictMode 为 true 时只在 bar 收盘时触发开仓判断，避免同一根 bar 内反复进出
+	StrictMode bool
+	// DryRun 为 true 时只记录意图订单，不调用 CreateOrder，也不会跟踪持仓状态
+	DryRun bool
+}
+
+// position 跟踪一次开仓的完整生命周期：入场单提交后等待成交（entryHash 非空），
+// 成交后转为带止盈止损两条腿的持仓，任一条腿成交则整单平仓、重新变回空仓。
+type position struct {
+	tokenID    string
+	size       float64
+	entryPrice float64
+
+	entryHash string
+	tpHash    string
+	slHash    string
+}
+
+// Strategy 把指标流与下单动作粘合在一起
+type Strategy struct {
+	client *polymarket.ClobClient
+	cfg    Config
+	book   *polymarket.ActiveOrderBook
+
+	cci *CCI
+	nr4 *NR4
+
+	mu  sync.Mutex
+	pos *position // nil 表示当前空仓，可以接受新的信号
+}
+
+// New 构造策略实例。book 应由调用方通过 polymarket.NewActiveOrderBook 构造并
+// Subscribe 到用户数据 WebSocket 流——没有真实的成交回报，策略就无法知道入场单
+// 何时成交、也无法在止盈/止损任一条腿成交后解除持仓状态。
+func New(client *polymarket.ClobClient, cfg Config, book *polymarket.ActiveOrderBook) *Strategy {
+	s := &Strategy{
+		client: client,
+		cfg:    cfg,
+		book:   book,
+		cci:    NewCCI(cfg.Window),
+		nr4:    NewNR4(cfg.BarInterval),
+	}
+	if book != nil {
+		book.OnFilled(s.handleFilled)
+	}
+	return s
+}
+
+// OnTick 消费一个新的 (bestBid, bestAsk) 报价，推进指标并在满足条件时开仓
+func (s *Strategy) OnTick(bestBid, bestAsk float64, t time.Time) error {
+	mid := (bestBid + bestAsk) / 2
+
+	s.cci.Update(mid)
+	barClosed := s.nr4.Update(mid, t)
+
+	if s.cfg.StrictMode && !barClosed {
+		return nil
+	}
+
+	s.mu.Lock()
+	busy := s.pos != nil
+	s.mu.Unlock()
+
+	if !s.cci.Ready() || busy {
+		return nil
+	}
+
+	switch {
+	case s.cci.Value() < -s.cfg.LongThreshold && s.nr4.Signal():
+		return s.enter(polymarket.BUY, s.cfg.TokenID, mid)
+	case s.cci.Value() > s.cfg.ShortThreshold:
+		return s.enter(polymarket.BUY, s.cfg.ComplementTokenID, 1-mid)
+	}
+
+	return nil
+}
+
+// enter 提交入场单并开始跟踪其成交回报；止盈止损在 handleFilled 收到入场单完全
+// 成交的回调后才会挂出，并使用入场单实际买到的 size，而不是按出场价重新算出的
+// 另一个 size。DryRun 模式下不跟踪任何状态，避免把策略永久卡在"持仓中"。
+func (s *Strategy) enter(side, tokenID string, entryPrice float64) error {
+	size := (s.cfg.Amount * s.cfg.Leverage) / entryPrice
+
+	args := &polymarket.OrderArgs{
+		TokenID: tokenID,
+		Side:    side,
+		Price:   entryPrice,
+		Size:    size,
+	}
+
+	if s.cfg.DryRun {
+		log.Printf("strategy: dry run entry %+v", args)
+		return nil
+	}
+
+	order, err := s.client.CreateOrder(args, nil)
+	if err != nil {
+		return fmt.Errorf("strategy: entry order: %w", err)
+	}
+	if _, err := s.client.SubmitOrder(order, polymarket.OrderTypeGTC); err != nil {
+		return fmt.Errorf("strategy: entry order: %w", err)
+	}
+
+	s.mu.Lock()
+	s.pos = &position{tokenID: tokenID, size: size, entryPrice: entryPrice, entryHash: order.Hash()}
+	s.mu.Unlock()
+
+	s.book.Track(order, tokenID, side, entryPrice, size)
+	return nil
+}
+
+// handleFilled 处理 ActiveOrderBook 的完全成交回调：入场单成交则挂出止盈止损两条腿；
+// 止盈或止损任一条腿成交则撤销另一条腿并把状态重新置为空仓，让 OnTick 可以再次开仓。
+func (s *Strategy) handleFilled(hash string) {
+	s.mu.Lock()
+	pos := s.pos
+	s.mu.Unlock()
+
+	if pos == nil {
+		return
+	}
+
+	switch hash {
+	case pos.entryHash:
+		s.onEntryFilled(pos)
+	case pos.tpHash:
+		s.onExitFilled(pos, pos.slHash)
+	case pos.slHash:
+		s.onExitFilled(pos, pos.tpHash)
+	}
+}
+
+// onEntryFilled 在入场单成交后，用实际成交的 size 挂出止盈止损两条腿
+func (s *Strategy) onEntryFilled(pos *position) {
+	tpHash := s.placeExit(pos.tokenID, pos.size, pos.entryPrice+s.cfg.ProfitRange)
+	slHash := s.placeExit(pos.tokenID, pos.size, pos.entryPrice-s.cfg.LossRange)
+
+	s.mu.Lock()
+	if s.pos == pos {
+		pos.entryHash = ""
+		pos.tpHash = tpHash
+		pos.slHash = slHash
+		// 两条腿都没能成功挂出，这次开仓已经没有出场计划，直接解除锁定
+		if tpHash == "" && slHash == "" {
+			s.pos = nil
+		}
+	}
+	s.mu.Unlock()
+}
+
+// placeExit 挂出一笔 GTC 限价 SELL 单作为止盈或止损出场，size 必须等于入场单实际
+// 成交的 size，而不是按出场价重新计算的另一个值，否则两条腿会卖出不同的份数。
+// 失败时记录日志并返回空哈希，不让整个策略因为一条腿下单失败而报错退出。
+func (s *Strategy) placeExit(tokenID string, size, exitPrice float64) string {
+	if exitPrice <= 0 || exitPrice >= 1 {
+		return ""
+	}
+
+	args := &polymarket.OrderArgs{
+		TokenID: tokenID,
+		Side:    polymarket.SELL,
+		Price:   exitPrice,
+		Size:    size,
+	}
+
+	if s.cfg.DryRun {
+		log.Printf("strategy: dry run exit %+v", args)
+		return ""
+	}
+
+	order, err := s.client.CreateOrder(args, nil)
+	if err != nil {
+		log.Printf("strategy: exit order: %v", err)
+		return ""
+	}
+	if _, err := s.client.SubmitOrder(order, polymarket.OrderTypeGTC); err != nil {
+		log.Printf("strategy: exit order: %v", err)
+		return ""
+	}
+
+	s.book.Track(order, tokenID, polymarket.SELL, exitPrice, size)
+	return order.Hash()
+}
+
+// onExitFilled 撤销仍在挂的另一条出场腿并把状态重新置为空仓，解除 OnTick 的锁定
+func (s *Strategy) onExitFilled(pos *position, otherHash string) {
+	if otherHash != "" {
+		if err := s.client.CancelPendingOrder(otherHash); err != nil {
+			log.Printf("strategy: cancel remaining exit leg %s: %v", otherHash, err)
+		}
+		s.book.Remove(otherHash)
+	}
+
+	s.mu.Lock()
+	if s.pos == pos {
+		s.pos = nil
+	}
+	s.mu.Unlock()
+}